@@ -0,0 +1,58 @@
+package xlru
+
+import (
+	"sync"
+	"time"
+)
+
+// typedLoadCall represents an in-flight or completed GetOrLoad call for a
+// single key, shared by every caller that misses on that key concurrently.
+type typedLoadCall[V any] struct {
+	wg    sync.WaitGroup
+	value V
+	err   error
+}
+
+// GetOrLoad returns the cached value for key, or calls loader to produce one
+// on a miss. Concurrent GetOrLoad calls for the same key coalesce onto a
+// single loader call; every caller receives its result. The value is only
+// inserted into the cache if loader succeeds.
+func (c *TypedCache[K, V]) GetOrLoad(key K, loader func() (V, time.Duration, error)) (V, error) {
+	if value, ok := c.Get(key); ok {
+		return value, nil
+	}
+
+	c.loadMu.Lock()
+	if call, ok := c.loads[key]; ok {
+		c.loadMu.Unlock()
+		call.wg.Wait()
+		return call.value, call.err
+	}
+
+	call := &typedLoadCall[V]{}
+	call.wg.Add(1)
+	if c.loads == nil {
+		c.loads = make(map[K]*typedLoadCall[V])
+	}
+	c.loads[key] = call
+	c.loadMu.Unlock()
+
+	value, ttl, err := loader()
+	if err == nil {
+		err = c.SetWithTTL(key, value, ttl)
+	}
+	call.value, call.err = value, err
+
+	c.loadMu.Lock()
+	delete(c.loads, key)
+	c.loadMu.Unlock()
+
+	call.wg.Done()
+	return call.value, call.err
+}
+
+// GetOrLoad returns the cached value for key, or calls loader to produce one
+// on a miss, as TypedCache.GetOrLoad.
+func (c *Cache) GetOrLoad(key string, loader func() ([]byte, time.Duration, error)) ([]byte, error) {
+	return c.inner.GetOrLoad(key, loader)
+}
@@ -0,0 +1,95 @@
+package xlru
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func Test_get_or_load_returns_cached_value_without_calling_loader(t *testing.T) {
+	cache := NewCache(100)
+	cache.SetBytes("key", ToBuffer("hello"), NoExpiration)
+
+	called := false
+	value, err := cache.GetOrLoad("key", func() ([]byte, time.Duration, error) {
+		called = true
+		return nil, NoExpiration, nil
+	})
+
+	if err != nil || FromBuffer(value) != "hello" {
+		t.Errorf("wrong value for \"key\": %s, %v", value, err)
+	}
+	if called {
+		t.Error("loader was called for a value already in the cache")
+	}
+}
+
+func Test_get_or_load_calls_loader_on_miss_and_caches_result(t *testing.T) {
+	cache := NewCache(100)
+
+	value, err := cache.GetOrLoad("key", func() ([]byte, time.Duration, error) {
+		return ToBuffer("loaded"), NoExpiration, nil
+	})
+
+	if err != nil || FromBuffer(value) != "loaded" {
+		t.Errorf("wrong value for \"key\": %s, %v", value, err)
+	}
+
+	if b, ok := cache.GetBytes("key"); !ok || FromBuffer(b) != "loaded" {
+		t.Error("loaded value was not cached")
+	}
+}
+
+func Test_get_or_load_does_not_cache_on_loader_error(t *testing.T) {
+	cache := NewCache(100)
+	loaderErr := errors.New("backing store unavailable")
+
+	_, err := cache.GetOrLoad("key", func() ([]byte, time.Duration, error) {
+		return nil, NoExpiration, loaderErr
+	})
+
+	if err != loaderErr {
+		t.Errorf("expected loader error, got %v", err)
+	}
+	if _, ok := cache.GetBytes("key"); ok {
+		t.Error("value was cached despite loader error")
+	}
+}
+
+func Test_get_or_load_coalesces_concurrent_misses(t *testing.T) {
+	cache := NewCache(100)
+
+	var calls int32
+	var ready sync.WaitGroup
+	ready.Add(1)
+
+	var wg sync.WaitGroup
+	results := make([]string, 10)
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			value, _ := cache.GetOrLoad("key", func() ([]byte, time.Duration, error) {
+				atomic.AddInt32(&calls, 1)
+				ready.Wait()
+				return ToBuffer("loaded"), NoExpiration, nil
+			})
+			results[i] = FromBuffer(value)
+		}(i)
+	}
+
+	ready.Done()
+	wg.Wait()
+
+	if calls != 1 {
+		t.Errorf("loader was called %v times, want 1", calls)
+	}
+	for i, r := range results {
+		if r != "loaded" {
+			t.Errorf("result[%d] = %q, want \"loaded\"", i, r)
+		}
+	}
+}
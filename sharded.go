@@ -0,0 +1,114 @@
+package xlru
+
+import (
+	"time"
+)
+
+// FNV-1a 32-bit constants, from hash/fnv. Inlined here so shardFor can hash
+// a key without allocating a hash.Hash32 on every call.
+const (
+	fnvOffset32 = 2166136261
+	fnvPrime32  = 16777619
+)
+
+// ShardedCache spreads keys across a fixed number of independent Caches,
+// each with its own mutex, to reduce lock contention under concurrent
+// workloads compared to a single Cache.
+type ShardedCache struct {
+	shards []*Cache
+	mask   uint32
+}
+
+// NewShardedCache creates a ShardedCache with shards rounded up to the next
+// power of two, each sized to an equal share of capacity.
+func NewShardedCache(capacity int64, shards int) *ShardedCache {
+	shards = nextPowerOfTwo(shards)
+
+	// Integer division: any remainder is dropped, so the enforced total
+	// capacity can be slightly less than capacity (e.g. 10 over 4 shards
+	// enforces 8). Pass a capacity that divides evenly to avoid this.
+	//
+	// If capacity is smaller than the shard count, perShard would truncate
+	// to 0 and every shard would reject all non-empty values with
+	// ErrValueTooLarge. Clamp to 1 so the cache stays usable, at the cost
+	// of the enforced total capacity exceeding capacity in that case.
+	perShard := capacity / int64(shards)
+	if perShard < 1 {
+		perShard = 1
+	}
+
+	sc := &ShardedCache{
+		shards: make([]*Cache, shards),
+		mask:   uint32(shards - 1),
+	}
+	for i := range sc.shards {
+		sc.shards[i] = NewCache(perShard)
+	}
+	return sc
+}
+
+func (sc *ShardedCache) GetBytes(key string) ([]byte, bool) {
+	return sc.shardFor(key).GetBytes(key)
+}
+
+func (sc *ShardedCache) SetBytes(key string, value []byte, expires time.Duration) error {
+	return sc.shardFor(key).SetBytes(key, value, expires)
+}
+
+func (sc *ShardedCache) Delete(key string) bool {
+	return sc.shardFor(key).Delete(key)
+}
+
+func (sc *ShardedCache) Clear() {
+	for _, shard := range sc.shards {
+		shard.Clear()
+	}
+}
+
+func (sc *ShardedCache) Keys() []string {
+	keys := make([]string, 0)
+	for _, shard := range sc.shards {
+		keys = append(keys, shard.Keys()...)
+	}
+	return keys
+}
+
+func (sc *ShardedCache) Stats() *Stats {
+	agg := &Stats{}
+	for _, shard := range sc.shards {
+		stats := shard.Stats()
+
+		agg.Count += stats.Count
+		agg.Size += stats.Size
+		agg.Capacity += stats.Capacity
+		agg.Hits += stats.Hits
+		agg.Misses += stats.Misses
+		agg.Evictions += stats.Evictions
+
+		if !stats.Oldest.IsZero() && (agg.Oldest.IsZero() || stats.Oldest.Before(agg.Oldest)) {
+			agg.Oldest = stats.Oldest
+		}
+	}
+	return agg
+}
+
+func (sc *ShardedCache) shardFor(key string) *Cache {
+	hash := uint32(fnvOffset32)
+	for i := 0; i < len(key); i++ {
+		hash ^= uint32(key[i])
+		hash *= fnvPrime32
+	}
+	return sc.shards[hash&sc.mask]
+}
+
+func nextPowerOfTwo(n int) int {
+	if n < 1 {
+		return 1
+	}
+
+	p := 1
+	for p < n {
+		p <<= 1
+	}
+	return p
+}
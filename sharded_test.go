@@ -0,0 +1,135 @@
+package xlru
+
+import (
+	"fmt"
+	"testing"
+)
+
+func Test_sharded_cache_set_and_get_value(t *testing.T) {
+	cache := NewShardedCache(100, 4)
+	cache.SetBytes("key", ToBuffer("hello"), NoExpiration)
+
+	b, ok := cache.GetBytes("key")
+	if !ok || FromBuffer(b) != "hello" {
+		t.Errorf("wrong value for \"key\": %s != %v", b, "hello")
+	}
+}
+
+func Test_sharded_cache_rounds_shards_up_to_power_of_two(t *testing.T) {
+	cache := NewShardedCache(100, 3)
+	if len(cache.shards) != 4 {
+		t.Errorf("shard count = %v, want 4", len(cache.shards))
+	}
+}
+
+func Test_sharded_cache_delete(t *testing.T) {
+	cache := NewShardedCache(100, 4)
+	cache.SetBytes("key", ToBuffer("hello"), NoExpiration)
+
+	if !cache.Delete("key") {
+		t.Error("expected value to be in cache")
+	}
+	if _, ok := cache.GetBytes("key"); ok {
+		t.Error("value was returned after deletion")
+	}
+}
+
+func Test_sharded_cache_clear(t *testing.T) {
+	cache := NewShardedCache(100, 4)
+	for i := 0; i < 20; i++ {
+		cache.SetBytes(fmt.Sprintf("key%d", i), ToBuffer("v"), NoExpiration)
+	}
+
+	cache.Clear()
+
+	if stats := cache.Stats(); stats.Count != 0 {
+		t.Errorf("count = %v, want 0", stats.Count)
+	}
+}
+
+func Test_sharded_cache_keys_merges_all_shards(t *testing.T) {
+	cache := NewShardedCache(1000, 4)
+	for i := 0; i < 20; i++ {
+		cache.SetBytes(fmt.Sprintf("key%d", i), ToBuffer("v"), NoExpiration)
+	}
+
+	if keys := cache.Keys(); len(keys) != 20 {
+		t.Errorf("number of keys = %v, want 20", len(keys))
+	}
+}
+
+func Test_sharded_cache_stats_aggregates_across_shards(t *testing.T) {
+	cache := NewShardedCache(1000, 4)
+	for i := 0; i < 20; i++ {
+		cache.SetBytes(fmt.Sprintf("key%d", i), ToBuffer("v"), NoExpiration)
+	}
+
+	stats := cache.Stats()
+	if stats.Count != 20 {
+		t.Errorf("count = %v, want 20", stats.Count)
+	}
+	if stats.Capacity != 1000 {
+		t.Errorf("capacity = %v, want 1000", stats.Capacity)
+	}
+}
+
+func Test_sharded_cache_clamps_per_shard_capacity_to_at_least_one(t *testing.T) {
+	cache := NewShardedCache(10, 16)
+
+	if err := cache.SetBytes("key", ToBuffer("v"), NoExpiration); err != nil {
+		t.Errorf("SetBytes failed: %v", err)
+	}
+}
+
+func Test_shard_for_does_not_allocate(t *testing.T) {
+	cache := NewShardedCache(100, 4)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		cache.shardFor("key")
+	})
+	if allocs != 0 {
+		t.Errorf("shardFor allocated %v times per call, want 0", allocs)
+	}
+}
+
+func benchmarkKeys(n int) []string {
+	keys := make([]string, n)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("key%d", i)
+	}
+	return keys
+}
+
+func Benchmark_single_cache_parallel_get_set(b *testing.B) {
+	cache := NewCache(1 << 20)
+	keys := benchmarkKeys(1000)
+	value := ToBuffer("value")
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			key := keys[i%len(keys)]
+			cache.SetBytes(key, value, NoExpiration)
+			cache.GetBytes(key)
+			i++
+		}
+	})
+}
+
+func Benchmark_sharded_cache_parallel_get_set(b *testing.B) {
+	cache := NewShardedCache(1<<20, 16)
+	keys := benchmarkKeys(1000)
+	value := ToBuffer("value")
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			key := keys[i%len(keys)]
+			cache.SetBytes(key, value, NoExpiration)
+			cache.GetBytes(key)
+			i++
+		}
+	})
+}
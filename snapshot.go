@@ -0,0 +1,134 @@
+package xlru
+
+import (
+	"encoding/gob"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// snapshotVersion guards against loading a snapshot written by an
+// incompatible future format.
+const snapshotVersion = 1
+
+type snapshotHeader struct {
+	Version int
+}
+
+type snapshotEntry struct {
+	Key     string
+	Value   []byte
+	Created time.Time
+	Expires time.Duration
+}
+
+// Save writes every live, unexpired entry to w, in LRU order, as a
+// versioned stream of gob-encoded values.
+func (c *Cache) Save(w io.Writer) error {
+	inner := c.inner
+
+	inner.mu.Lock()
+	defer inner.mu.Unlock()
+
+	enc := gob.NewEncoder(w)
+	if err := enc.Encode(snapshotHeader{Version: snapshotVersion}); err != nil {
+		return err
+	}
+
+	for el := inner.list.Front(); el != nil; el = el.Next() {
+		entry := el.Value.(*typedEntry[string, []byte])
+		if entry.expired() {
+			continue
+		}
+
+		se := snapshotEntry{
+			Key:     entry.key,
+			Value:   entry.value,
+			Created: entry.created,
+			Expires: entry.expires,
+		}
+		if err := enc.Encode(se); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SaveFile writes a snapshot to path, as Save.
+func (c *Cache) SaveFile(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return c.Save(f)
+}
+
+// Load inserts every entry read from r, restoring LRU order from
+// serialization order. Entries whose remaining TTL has elapsed since they
+// were saved are skipped. As with SetBytes, loading a key that already
+// exists in the cache only replaces its value, keeping its current
+// expiration rather than the snapshot's.
+func (c *Cache) Load(r io.Reader) error {
+	dec := gob.NewDecoder(r)
+
+	var header snapshotHeader
+	if err := dec.Decode(&header); err != nil {
+		return err
+	}
+	if header.Version != snapshotVersion {
+		return fmt.Errorf("xlru: unsupported snapshot version %d", header.Version)
+	}
+
+	var entries []snapshotEntry
+	for {
+		var se snapshotEntry
+		if err := dec.Decode(&se); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return err
+		}
+		entries = append(entries, se)
+	}
+
+	inner := c.inner
+	var pending []typedEvictedEntry[string, []byte]
+
+	inner.mu.Lock()
+	for i := len(entries) - 1; i >= 0; i-- {
+		se := entries[i]
+
+		expires := se.Expires
+		if expires != NoExpiration {
+			expires = time.Until(se.Created.Add(se.Expires))
+			if expires <= 0 {
+				continue
+			}
+		}
+
+		if element := inner.table[se.Key]; element != nil {
+			inner.update(element, se.Value, int64(len(se.Value)), &pending)
+		} else {
+			inner.insert(se.Key, se.Value, int64(len(se.Value)), expires, &pending)
+		}
+	}
+	inner.mu.Unlock()
+
+	inner.fireEvicted(pending)
+	return nil
+}
+
+// LoadFile reads a snapshot from path, as Load.
+func (c *Cache) LoadFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return c.Load(f)
+}
@@ -0,0 +1,121 @@
+package xlru
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func Test_save_and_load_restores_values(t *testing.T) {
+	cache := NewCache(100)
+	cache.SetBytes("key1", ToBuffer("a"), NoExpiration)
+	cache.SetBytes("key2", ToBuffer("b"), NoExpiration)
+
+	var buf bytes.Buffer
+	if err := cache.Save(&buf); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	restored := NewCache(100)
+	if err := restored.Load(&buf); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if b, ok := restored.GetBytes("key1"); !ok || FromBuffer(b) != "a" {
+		t.Errorf("wrong value for \"key1\": %s", b)
+	}
+	if b, ok := restored.GetBytes("key2"); !ok || FromBuffer(b) != "b" {
+		t.Errorf("wrong value for \"key2\": %s", b)
+	}
+}
+
+func Test_save_and_load_preserves_lru_order(t *testing.T) {
+	cache := NewCache(100)
+	cache.SetBytes("key1", ToBuffer("a"), NoExpiration)
+	cache.SetBytes("key2", ToBuffer("b"), NoExpiration)
+	cache.SetBytes("key3", ToBuffer("c"), NoExpiration)
+	// lru: [key3, key2, key1]
+
+	var buf bytes.Buffer
+	cache.Save(&buf)
+
+	restored := NewCache(100)
+	restored.Load(&buf)
+
+	if keys := restored.Keys(); keys[0] != "key3" || keys[1] != "key2" || keys[2] != "key1" {
+		t.Errorf("wrong key order after restore: %v", keys)
+	}
+}
+
+func Test_save_skips_already_expired_entries(t *testing.T) {
+	cache := NewCache(100)
+	cache.SetBytes("key", ToBuffer("hello"), 1*time.Millisecond)
+	<-time.After(2 * time.Millisecond)
+
+	var buf bytes.Buffer
+	cache.Save(&buf)
+
+	restored := NewCache(100)
+	restored.Load(&buf)
+
+	if _, ok := restored.GetBytes("key"); ok {
+		t.Error("expired value should not have been saved")
+	}
+}
+
+func Test_load_skips_entries_expired_since_they_were_saved(t *testing.T) {
+	cache := NewCache(100)
+	cache.SetBytes("key", ToBuffer("hello"), 5*time.Millisecond)
+
+	var buf bytes.Buffer
+	cache.Save(&buf)
+
+	<-time.After(10 * time.Millisecond)
+
+	restored := NewCache(100)
+	restored.Load(&buf)
+
+	if _, ok := restored.GetBytes("key"); ok {
+		t.Error("value that expired before load should have been skipped")
+	}
+}
+
+func Test_load_over_existing_key_keeps_its_current_expiration(t *testing.T) {
+	cache := NewCache(100)
+	cache.SetBytes("key", ToBuffer("old"), NoExpiration)
+
+	var buf bytes.Buffer
+	saved := NewCache(100)
+	saved.SetBytes("key", ToBuffer("new"), 1*time.Millisecond)
+	saved.Save(&buf)
+
+	cache.Load(&buf)
+	<-time.After(2 * time.Millisecond)
+
+	// The snapshot's 1ms TTL is documented to be ignored for a key that
+	// already existed in the target cache; the value updates but the
+	// original NoExpiration survives.
+	if b, ok := cache.GetBytes("key"); !ok || FromBuffer(b) != "new" {
+		t.Errorf("wrong value for \"key\": %s", b)
+	}
+}
+
+func Test_save_file_and_load_file_round_trip(t *testing.T) {
+	cache := NewCache(100)
+	cache.SetBytes("key", ToBuffer("hello"), NoExpiration)
+
+	path := filepath.Join(t.TempDir(), "snapshot.gob")
+	if err := cache.SaveFile(path); err != nil {
+		t.Fatalf("SaveFile failed: %v", err)
+	}
+
+	restored := NewCache(100)
+	if err := restored.LoadFile(path); err != nil {
+		t.Fatalf("LoadFile failed: %v", err)
+	}
+
+	if b, ok := restored.GetBytes("key"); !ok || FromBuffer(b) != "hello" {
+		t.Errorf("wrong value for \"key\": %s", b)
+	}
+}
@@ -0,0 +1,484 @@
+package xlru
+
+import (
+	"container/heap"
+	"container/list"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// TypedCache is an LRU cache parameterized over key and value types, for
+// callers that don't want to marshal values to []byte. The byte-slice Cache
+// is implemented as a TypedCache[string, []byte] with SizeOf set to len.
+//
+// Capacity is a plain entry count unless SetSizeOf is used to measure it in
+// bytes (or any other unit) instead.
+type TypedCache[K comparable, V any] struct {
+	mu       sync.Mutex
+	size     int64
+	capacity int64
+
+	list  *list.List
+	table map[K]*list.Element
+
+	expirations typedExpirationHeap[K, V]
+	sizeOf      func(V) int64
+
+	onEvicted func(key K, value V, reason EvictReason)
+
+	hits      atomic.Int64
+	misses    atomic.Int64
+	evictions atomic.Int64
+
+	// in-flight GetOrLoad calls, keyed so concurrent misses on the same
+	// key share a single loader call
+	loadMu sync.Mutex
+	loads  map[K]*typedLoadCall[V]
+
+	// background janitor, started by NewCacheWithJanitor
+	janitorDone chan struct{}
+	janitorWG   sync.WaitGroup
+}
+
+type typedEntry[K comparable, V any] struct {
+	key     K
+	value   V
+	size    int64
+	created time.Time
+	touched time.Time
+	expires time.Duration
+
+	// index within the expirations heap, or -1 if expires is NoExpiration
+	heapIndex int
+}
+
+// typedEvictedEntry is an entry that left the cache while the mutex was
+// held, queued so its onEvicted callback can fire after the mutex is
+// released.
+type typedEvictedEntry[K comparable, V any] struct {
+	key    K
+	value  V
+	reason EvictReason
+}
+
+// New creates a TypedCache with the given capacity.
+func New[K comparable, V any](capacity int) *TypedCache[K, V] {
+	return newTypedCache[K, V](int64(capacity))
+}
+
+// NewWithJanitor creates a TypedCache that also runs a background goroutine,
+// waking every cleanupInterval, to evict expired entries even if they're
+// never touched by a Get or Set. Callers must call Close to stop it.
+func NewWithJanitor[K comparable, V any](capacity int, cleanupInterval time.Duration) *TypedCache[K, V] {
+	c := New[K, V](capacity)
+	c.startJanitor(cleanupInterval)
+	return c
+}
+
+func newTypedCache[K comparable, V any](capacity int64) *TypedCache[K, V] {
+	return &TypedCache[K, V]{
+		list:     list.New(),
+		table:    make(map[K]*list.Element),
+		capacity: capacity,
+	}
+}
+
+// SetSizeOf installs a function used to measure the size of values so
+// capacity can be enforced in bytes (or any other unit) rather than by
+// entry count. A nil sizeOf restores count-based capacity.
+func (c *TypedCache[K, V]) SetSizeOf(sizeOf func(V) int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sizeOf = sizeOf
+}
+
+// SetOnEvicted installs a callback invoked once per entry that leaves the
+// cache, after the mutex protecting it has been released.
+func (c *TypedCache[K, V]) SetOnEvicted(fn func(key K, value V, reason EvictReason)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.onEvicted = fn
+}
+
+func (c *TypedCache[K, V]) Get(key K) (value V, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element := c.table[key]
+	if element == nil {
+		c.misses.Add(1)
+		return value, false
+	}
+
+	entry := element.Value.(*typedEntry[K, V])
+	if entry.expired() {
+		c.misses.Add(1)
+		return value, false
+	}
+
+	c.touch(element)
+	c.hits.Add(1)
+	return entry.value, true
+}
+
+func (c *TypedCache[K, V]) Peek(key K) (value V, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element := c.table[key]
+	if element == nil {
+		return value, false
+	}
+
+	entry := element.Value.(*typedEntry[K, V])
+	if entry.expired() {
+		return value, false
+	}
+
+	return entry.value, true
+}
+
+func (c *TypedCache[K, V]) Contains(key K) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element := c.table[key]
+	if element == nil {
+		return false
+	}
+
+	return !element.Value.(*typedEntry[K, V]).expired()
+}
+
+func (c *TypedCache[K, V]) Set(key K, value V) error {
+	return c.SetWithTTL(key, value, NoExpiration)
+}
+
+func (c *TypedCache[K, V]) SetWithTTL(key K, value V, ttl time.Duration) error {
+	var pending []typedEvictedEntry[K, V]
+
+	c.mu.Lock()
+	size := c.entrySize(value)
+	if size > c.capacity {
+		c.mu.Unlock()
+		return ErrValueTooLarge
+	}
+
+	if element := c.table[key]; element != nil {
+		c.update(element, value, size, &pending)
+	} else {
+		c.insert(key, value, size, ttl, &pending)
+	}
+	c.mu.Unlock()
+
+	c.fireEvicted(pending)
+	return nil
+}
+
+func (c *TypedCache[K, V]) Remove(key K) bool {
+	c.mu.Lock()
+	element := c.table[key]
+	if element == nil {
+		c.mu.Unlock()
+		return false
+	}
+
+	var pending []typedEvictedEntry[K, V]
+	c.removeElement(element, EvictExplicit, &pending)
+	c.mu.Unlock()
+
+	c.fireEvicted(pending)
+	return true
+}
+
+func (c *TypedCache[K, V]) Clear() {
+	c.mu.Lock()
+
+	pending := make([]typedEvictedEntry[K, V], 0, c.list.Len())
+	for el := c.list.Front(); el != nil; el = el.Next() {
+		entry := el.Value.(*typedEntry[K, V])
+		pending = append(pending, typedEvictedEntry[K, V]{entry.key, entry.value, EvictCleared})
+	}
+
+	c.list.Init()
+	c.table = make(map[K]*list.Element)
+	c.expirations = nil
+	c.size = 0
+	c.mu.Unlock()
+
+	c.fireEvicted(pending)
+}
+
+func (c *TypedCache[K, V]) Keys() []K {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	keys := make([]K, 0, c.list.Len())
+	for e := c.list.Front(); e != nil; e = e.Next() {
+		keys = append(keys, e.Value.(*typedEntry[K, V]).key)
+	}
+	return keys
+}
+
+func (c *TypedCache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.list.Len()
+}
+
+func (c *TypedCache[K, V]) Size() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.size
+}
+
+func (c *TypedCache[K, V]) Capacity() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.capacity
+}
+
+func (c *TypedCache[K, V]) Stats() *Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var oldest time.Time
+	var size int64
+	var count int64
+
+	for el := c.list.Back(); el != nil; el = el.Prev() {
+		entry := el.Value.(*typedEntry[K, V])
+		if entry.expired() {
+			continue
+		}
+
+		count += 1
+		size += entry.size
+		if oldest.IsZero() {
+			oldest = entry.touched
+		}
+	}
+
+	return &Stats{
+		Count:     count,
+		Size:      size,
+		Capacity:  c.capacity,
+		Oldest:    oldest,
+		Hits:      c.hits.Load(),
+		Misses:    c.misses.Load(),
+		Evictions: c.evictions.Load(),
+	}
+}
+
+// Close stops the background janitor, if one was started with
+// NewWithJanitor, and waits for it to exit. It is safe to call on a
+// TypedCache without a janitor, and safe to call more than once.
+func (c *TypedCache[K, V]) Close() error {
+	c.mu.Lock()
+	done := c.janitorDone
+	c.janitorDone = nil
+	c.mu.Unlock()
+
+	if done != nil {
+		close(done)
+		c.janitorWG.Wait()
+	}
+	return nil
+}
+
+func (c *TypedCache[K, V]) startJanitor(interval time.Duration) {
+	c.janitorDone = make(chan struct{})
+	done := c.janitorDone
+
+	c.janitorWG.Add(1)
+	go func() {
+		defer c.janitorWG.Done()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				var pending []typedEvictedEntry[K, V]
+				c.mu.Lock()
+				c.evictExpired(&pending)
+				c.mu.Unlock()
+				c.fireEvicted(pending)
+			case <-done:
+				return
+			}
+		}
+	}()
+}
+
+func (c *TypedCache[K, V]) entrySize(value V) int64 {
+	if c.sizeOf == nil {
+		return 1
+	}
+	return c.sizeOf(value)
+}
+
+func (c *TypedCache[K, V]) update(element *list.Element, value V, size int64, pending *[]typedEvictedEntry[K, V]) {
+	old := element.Value.(*typedEntry[K, V])
+	oldValue := old.value
+
+	difference := size - old.size
+	old.value = value
+	old.size = size
+	c.size += difference
+	c.touch(element)
+
+	c.recordEviction(old.key, oldValue, EvictReplaced, pending)
+	c.enforceCapacity(pending)
+}
+
+func (c *TypedCache[K, V]) insert(key K, value V, size int64, expires time.Duration, pending *[]typedEvictedEntry[K, V]) {
+	now := time.Now()
+	entry := &typedEntry[K, V]{
+		key:       key,
+		value:     value,
+		size:      size,
+		created:   now,
+		touched:   now,
+		expires:   expires,
+		heapIndex: -1,
+	}
+
+	element := c.list.PushFront(entry)
+	c.table[key] = element
+	c.size += entry.size
+
+	if expires != NoExpiration {
+		heap.Push(&c.expirations, entry)
+	}
+
+	c.enforceCapacity(pending)
+}
+
+func (c *TypedCache[K, V]) touch(element *list.Element) {
+	c.list.MoveToFront(element)
+	element.Value.(*typedEntry[K, V]).touched = time.Now()
+}
+
+// removeElement removes el from the list, table, and (if present) the
+// expirations heap, adjusts size accordingly, and queues an eviction for
+// fireEvicted.
+func (c *TypedCache[K, V]) removeElement(el *list.Element, reason EvictReason, pending *[]typedEvictedEntry[K, V]) {
+	entry := el.Value.(*typedEntry[K, V])
+
+	c.list.Remove(el)
+	delete(c.table, entry.key)
+	c.size -= entry.size
+
+	if entry.heapIndex >= 0 {
+		heap.Remove(&c.expirations, entry.heapIndex)
+	}
+
+	c.recordEviction(entry.key, entry.value, reason, pending)
+}
+
+// recordEviction increments the eviction counter and queues the entry so its
+// onEvicted callback can fire once the mutex is released.
+func (c *TypedCache[K, V]) recordEviction(key K, value V, reason EvictReason, pending *[]typedEvictedEntry[K, V]) {
+	c.evictions.Add(1)
+	*pending = append(*pending, typedEvictedEntry[K, V]{key, value, reason})
+}
+
+// evictExpired removes entries whose deadline has already passed, using the
+// expirations heap to find them in O(log n) per eviction rather than
+// scanning the whole list.
+func (c *TypedCache[K, V]) evictExpired(pending *[]typedEvictedEntry[K, V]) {
+	now := time.Now()
+	for len(c.expirations) > 0 {
+		entry := c.expirations[0]
+		if entry.deadline().After(now) {
+			return
+		}
+
+		if el := c.table[entry.key]; el != nil {
+			c.removeElement(el, EvictExpired, pending)
+		}
+	}
+}
+
+func (c *TypedCache[K, V]) enforceCapacity(pending *[]typedEvictedEntry[K, V]) {
+	c.evictExpired(pending)
+
+	// evict least recently used
+	for c.size > c.capacity {
+		last := c.list.Back()
+		if last == nil {
+			break
+		}
+
+		c.removeElement(last, EvictLRU, pending)
+	}
+}
+
+// fireEvicted invokes the onEvicted callback, if one is installed, for each
+// queued eviction. It must be called without the mutex held.
+func (c *TypedCache[K, V]) fireEvicted(pending []typedEvictedEntry[K, V]) {
+	if len(pending) == 0 {
+		return
+	}
+
+	c.mu.Lock()
+	onEvicted := c.onEvicted
+	c.mu.Unlock()
+
+	if onEvicted == nil {
+		return
+	}
+
+	for _, e := range pending {
+		onEvicted(e.key, e.value, e.reason)
+	}
+}
+
+func (e *typedEntry[K, V]) deadline() time.Time {
+	return e.created.Add(e.expires)
+}
+
+func (e *typedEntry[K, V]) expired() bool {
+	if e.expires == NoExpiration {
+		return false
+	}
+
+	return time.Now().After(e.deadline())
+}
+
+// typedExpirationHeap is a container/heap of entries ordered by deadline,
+// with NoExpiration entries never added. It implements heap.Interface.
+type typedExpirationHeap[K comparable, V any] []*typedEntry[K, V]
+
+func (h typedExpirationHeap[K, V]) Len() int { return len(h) }
+
+func (h typedExpirationHeap[K, V]) Less(i, j int) bool {
+	return h[i].deadline().Before(h[j].deadline())
+}
+
+func (h typedExpirationHeap[K, V]) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].heapIndex = i
+	h[j].heapIndex = j
+}
+
+func (h *typedExpirationHeap[K, V]) Push(x any) {
+	entry := x.(*typedEntry[K, V])
+	entry.heapIndex = len(*h)
+	*h = append(*h, entry)
+}
+
+func (h *typedExpirationHeap[K, V]) Pop() any {
+	old := *h
+	n := len(old)
+
+	entry := old[n-1]
+	old[n-1] = nil
+	entry.heapIndex = -1
+
+	*h = old[:n-1]
+	return entry
+}
@@ -0,0 +1,156 @@
+package xlru
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func Test_typed_initial_state(t *testing.T) {
+	cache := New[string, int](5)
+
+	if cache.Len() != 0 {
+		t.Errorf("number of values = %v, want 0", cache.Len())
+	}
+}
+
+func Test_typed_set_and_get_value(t *testing.T) {
+	cache := New[string, int](100)
+	cache.Set("key", 42)
+
+	v, ok := cache.Get("key")
+	if !ok || v != 42 {
+		t.Errorf("wrong value for \"key\": %v != %v", v, 42)
+	}
+}
+
+func Test_typed_get_missing_value(t *testing.T) {
+	cache := New[string, int](100)
+
+	if _, ok := cache.Get("blah"); ok {
+		t.Error("empty cache returned a value")
+	}
+}
+
+func Test_typed_peek_does_not_affect_lru_order(t *testing.T) {
+	cache := New[string, int](2)
+
+	cache.Set("key1", 1)
+	cache.Set("key2", 2)
+	// lru: [key2, key1]
+
+	cache.Peek("key1")
+	// peeking key1 should not move it to the front
+
+	cache.Set("key3", 3)
+	// key1 is still least recently used and should be evicted
+
+	if _, ok := cache.Get("key1"); ok {
+		t.Error("value key1 was not evicted")
+	}
+	if _, ok := cache.Get("key2"); !ok {
+		t.Error("value key2 is missing")
+	}
+}
+
+func Test_typed_contains(t *testing.T) {
+	cache := New[string, int](100)
+
+	if cache.Contains("key") {
+		t.Error("empty cache contains a value")
+	}
+
+	cache.Set("key", 1)
+	if !cache.Contains("key") {
+		t.Error("expected cache to contain \"key\"")
+	}
+}
+
+func Test_typed_remove(t *testing.T) {
+	cache := New[string, int](100)
+	if cache.Remove("key") {
+		t.Error("value unexpectedly already in cache")
+	}
+
+	cache.Set("key", 1)
+	if !cache.Remove("key") {
+		t.Error("expected value to be in cache")
+	}
+
+	if cache.Contains("key") {
+		t.Error("value was returned after removal")
+	}
+}
+
+func Test_typed_set_with_ttl_expires(t *testing.T) {
+	cache := New[string, int](100)
+	cache.SetWithTTL("key", 1, 1*time.Millisecond)
+	<-time.After(2 * time.Millisecond)
+
+	if _, ok := cache.Get("key"); ok {
+		t.Error("expired value should have been evicted")
+	}
+}
+
+func Test_typed_capacity_limit(t *testing.T) {
+	cache := New[string, int](3)
+
+	cache.Set("key1", 1)
+	cache.Set("key2", 2)
+	cache.Set("key3", 3)
+
+	if cache.Len() != 3 {
+		t.Errorf("cache length (%v) should be 3", cache.Len())
+	}
+
+	cache.Set("key4", 4)
+	if cache.Len() != 3 {
+		t.Errorf("cache length (%v) should be 3", cache.Len())
+	}
+
+	if _, ok := cache.Get("key1"); ok {
+		t.Error("least recently used value was not evicted")
+	}
+}
+
+func Test_typed_size_of_measures_capacity_in_bytes(t *testing.T) {
+	cache := New[string, []byte](2)
+	cache.SetSizeOf(func(b []byte) int64 { return int64(len(b)) })
+
+	if err := cache.Set("key", []byte("abc")); err != ErrValueTooLarge {
+		t.Errorf("expected ErrValueTooLarge, but was %v", err)
+	}
+}
+
+func Test_typed_set_size_of_concurrently_with_set_does_not_race(t *testing.T) {
+	cache := New[string, []byte](100)
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			cache.SetSizeOf(func(b []byte) int64 { return int64(len(b)) })
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			cache.Set("key", []byte("value"))
+		}
+	}()
+
+	wg.Wait()
+}
+
+func Test_typed_keys(t *testing.T) {
+	cache := New[string, int](100)
+	cache.Set("key1", 1)
+	cache.Set("key2", 2)
+
+	keys := cache.Keys()
+	if len(keys) != 2 {
+		t.Errorf("number of keys = %v, want 2", len(keys))
+	}
+}
@@ -1,9 +1,7 @@
 package xlru
 
 import (
-	"container/list"
 	"errors"
-	"sync"
 	"time"
 )
 
@@ -11,211 +9,94 @@ const NoExpiration = 0 * time.Millisecond
 
 var ErrValueTooLarge = errors.New("xlru: the value is larger than capacity")
 
-type Cache struct {
-	mu       sync.Mutex
-	size     int64
-	capacity int64
+// EvictReason identifies why an entry left the cache, passed to the
+// callback installed with SetOnEvicted.
+type EvictReason int
 
-	// list & table of cache entries
-	list  *list.List
-	table map[string]*list.Element
-}
+const (
+	EvictLRU EvictReason = iota
+	EvictExpired
+	EvictReplaced
+	EvictExplicit
+	EvictCleared
+)
 
 type Stats struct {
-	Count    int64
-	Size     int64
-	Capacity int64
-	Oldest   time.Time
+	Count     int64
+	Size      int64
+	Capacity  int64
+	Oldest    time.Time
+	Hits      int64
+	Misses    int64
+	Evictions int64
 }
 
-type entry struct {
-	key     string
-	value   []byte
-	size    int64
-	created time.Time
-	touched time.Time
-	expires time.Duration
+// Cache is a byte-slice LRU cache: a TypedCache[string, []byte] with
+// SizeOf set to len, so capacity is measured in bytes.
+type Cache struct {
+	inner *TypedCache[string, []byte]
 }
 
 func NewCache(capacity int64) *Cache {
-	return &Cache{
-		list:     list.New(),
-		table:    make(map[string]*list.Element),
-		capacity: capacity,
-	}
+	inner := newTypedCache[string, []byte](capacity)
+	inner.SetSizeOf(func(b []byte) int64 { return int64(len(b)) })
+	return &Cache{inner: inner}
 }
 
-func (c *Cache) GetBytes(key string) (b []byte, ok bool) {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
-	element := c.table[key]
-	if element == nil {
-		return nil, false
-	}
+// NewCacheWithJanitor creates a Cache that also runs a background goroutine,
+// waking every cleanupInterval, to evict expired entries even if they're
+// never touched by a Get or Set. Callers must call Close to stop it.
+func NewCacheWithJanitor(capacity int64, cleanupInterval time.Duration) *Cache {
+	c := NewCache(capacity)
+	c.inner.startJanitor(cleanupInterval)
+	return c
+}
 
-	entry := element.Value.(*entry)
-	if entry.expired() {
-		return nil, false
-	}
+// SetOnEvicted installs a callback invoked once per entry that leaves the
+// cache, after the mutex protecting it has been released.
+func (c *Cache) SetOnEvicted(fn func(key string, value []byte, reason EvictReason)) {
+	c.inner.SetOnEvicted(fn)
+}
 
-	c.touch(element)
-	return entry.value, true
+func (c *Cache) GetBytes(key string) ([]byte, bool) {
+	return c.inner.Get(key)
 }
 
 func (c *Cache) SetBytes(key string, value []byte, expires time.Duration) error {
-	if int64(len(value)) > c.capacity {
-		return ErrValueTooLarge
-	}
-
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
-	if element := c.table[key]; element != nil {
-		c.update(element, value)
-	} else {
-		c.insert(key, value, expires)
-	}
-
-	return nil
+	return c.inner.SetWithTTL(key, value, expires)
 }
 
 func (c *Cache) Delete(key string) bool {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
-	element := c.table[key]
-	if element == nil {
-		return false
-	}
-
-	c.list.Remove(element)
-	delete(c.table, key)
-	c.size -= element.Value.(*entry).size
-	return true
+	return c.inner.Remove(key)
 }
 
 func (c *Cache) Clear() {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
-	c.list.Init()
-	c.table = make(map[string]*list.Element)
-	c.size = 0
+	c.inner.Clear()
 }
 
 func (c *Cache) Count() int64 {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-	return int64(c.list.Len())
+	return int64(c.inner.Len())
 }
 
 func (c *Cache) Size() int64 {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-	return c.size
+	return c.inner.Size()
 }
 
 func (c *Cache) Capacity() int64 {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-	return c.capacity
+	return c.inner.Capacity()
 }
 
 func (c *Cache) Keys() []string {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
-	keys := make([]string, 0, c.list.Len())
-	for e := c.list.Front(); e != nil; e = e.Next() {
-		keys = append(keys, e.Value.(*entry).key)
-	}
-	return keys
+	return c.inner.Keys()
 }
 
 func (c *Cache) Stats() *Stats {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
-	var oldest time.Time
-	var size int64
-	var count int64
-
-	for el := c.list.Back(); el != nil; el = el.Prev() {
-		entry := el.Value.(*entry)
-		if entry.expired() {
-			continue
-		}
-
-		count += 1
-		size += entry.size
-		if oldest.IsZero() {
-			oldest = entry.touched
-		}
-	}
-
-	return &Stats{count, size, c.capacity, oldest}
-}
-
-func (c *Cache) update(element *list.Element, value []byte) {
-	size := int64(len(value))
-	difference := size - element.Value.(*entry).size
-	element.Value.(*entry).value = value
-	element.Value.(*entry).size = size
-	c.size += difference
-	c.touch(element)
-	c.enforceCapacity()
+	return c.inner.Stats()
 }
 
-func (c *Cache) insert(key string, value []byte, expires time.Duration) {
-	now := time.Now()
-	size := int64(len(value))
-	entry := &entry{key, value, size, now, now, expires}
-	element := c.list.PushFront(entry)
-	c.table[key] = element
-	c.size += entry.size
-	c.enforceCapacity()
-}
-
-func (c *Cache) touch(element *list.Element) {
-	c.list.MoveToFront(element)
-	element.Value.(*entry).touched = time.Now()
-}
-
-func (c *Cache) enforceCapacity() {
-	// evict expired values
-	for el := c.list.Back(); el != nil; el = el.Prev() {
-		entry := el.Value.(*entry)
-
-		if entry.expired() {
-			c.list.Remove(el)
-			delete(c.table, entry.key)
-
-			c.size -= entry.size
-		}
-	}
-
-	// evict least recently used
-	for c.size > c.capacity {
-		last := c.list.Back()
-		entry := last.Value.(*entry)
-
-		c.list.Remove(last)
-		delete(c.table, entry.key)
-
-		c.size -= entry.size
-	}
-}
-
-func (e *entry) expired() bool {
-	if e.expires == NoExpiration {
-		return false
-	}
-
-	deadline := e.created.Add(e.expires)
-	if time.Now().After(deadline) {
-		return true
-	}
-
-	return false
+// Close stops the background janitor, if one was started with
+// NewCacheWithJanitor, and waits for it to exit. It is safe to call on a
+// Cache without a janitor, and safe to call more than once.
+func (c *Cache) Close() error {
+	return c.inner.Close()
 }
@@ -266,6 +266,141 @@ func Test_expired_value_evicted_before_least_recently_used_value(t *testing.T) {
 	}
 }
 
+func Test_stats_counts_hits_and_misses(t *testing.T) {
+	cache := NewCache(100)
+	cache.SetBytes("key", ToBuffer("hello"), NoExpiration)
+
+	cache.GetBytes("key")
+	cache.GetBytes("key")
+	cache.GetBytes("missing")
+
+	stats := cache.Stats()
+	if stats.Hits != 2 {
+		t.Errorf("hits = %v, want 2", stats.Hits)
+	}
+	if stats.Misses != 1 {
+		t.Errorf("misses = %v, want 1", stats.Misses)
+	}
+}
+
+func Test_stats_counts_evictions(t *testing.T) {
+	cache := NewCache(2)
+	cache.SetBytes("key1", ToBuffer("a"), NoExpiration)
+	cache.SetBytes("key2", ToBuffer("b"), NoExpiration)
+	cache.SetBytes("key3", ToBuffer("c"), NoExpiration) // evicts key1
+
+	if stats := cache.Stats(); stats.Evictions != 1 {
+		t.Errorf("evictions = %v, want 1", stats.Evictions)
+	}
+}
+
+func Test_on_evicted_is_called_for_explicit_delete(t *testing.T) {
+	cache := NewCache(100)
+	cache.SetBytes("key", ToBuffer("hello"), NoExpiration)
+
+	var gotKey string
+	var gotReason EvictReason
+	cache.SetOnEvicted(func(key string, value []byte, reason EvictReason) {
+		gotKey = key
+		gotReason = reason
+	})
+
+	cache.Delete("key")
+
+	if gotKey != "key" || gotReason != EvictExplicit {
+		t.Errorf("onEvicted called with (%v, %v), want (key, EvictExplicit)", gotKey, gotReason)
+	}
+}
+
+func Test_on_evicted_is_called_for_lru_eviction(t *testing.T) {
+	cache := NewCache(2)
+	cache.SetBytes("key1", ToBuffer("a"), NoExpiration)
+	cache.SetBytes("key2", ToBuffer("b"), NoExpiration)
+
+	var gotReason EvictReason
+	cache.SetOnEvicted(func(key string, value []byte, reason EvictReason) {
+		gotReason = reason
+	})
+
+	cache.SetBytes("key3", ToBuffer("c"), NoExpiration)
+
+	if gotReason != EvictLRU {
+		t.Errorf("onEvicted reason = %v, want EvictLRU", gotReason)
+	}
+}
+
+func Test_on_evicted_is_called_for_replaced_value(t *testing.T) {
+	cache := NewCache(100)
+	cache.SetBytes("key", ToBuffer("old"), NoExpiration)
+
+	var gotValue string
+	var gotReason EvictReason
+	cache.SetOnEvicted(func(key string, value []byte, reason EvictReason) {
+		gotValue = FromBuffer(value)
+		gotReason = reason
+	})
+
+	cache.SetBytes("key", ToBuffer("new"), NoExpiration)
+
+	if gotValue != "old" || gotReason != EvictReplaced {
+		t.Errorf("onEvicted called with (%v, %v), want (old, EvictReplaced)", gotValue, gotReason)
+	}
+}
+
+func Test_on_evicted_is_called_for_cleared_values(t *testing.T) {
+	cache := NewCache(100)
+	cache.SetBytes("key", ToBuffer("hello"), NoExpiration)
+
+	calls := 0
+	cache.SetOnEvicted(func(key string, value []byte, reason EvictReason) {
+		calls++
+		if reason != EvictCleared {
+			t.Errorf("reason = %v, want EvictCleared", reason)
+		}
+	})
+
+	cache.Clear()
+
+	if calls != 1 {
+		t.Errorf("onEvicted called %v times, want 1", calls)
+	}
+}
+
+func Test_janitor_evicts_expired_values_in_background(t *testing.T) {
+	cache := NewCacheWithJanitor(100, 1*time.Millisecond)
+	defer cache.Close()
+
+	cache.SetBytes("key", ToBuffer("hello"), 1*time.Millisecond)
+	<-time.After(10 * time.Millisecond)
+
+	// Count includes expired entries that have not been evicted yet, unlike
+	// Stats().Count, so it only drops to 0 if the janitor removed the entry.
+	if count := cache.Count(); count != 0 {
+		t.Errorf("number of values should be 0, but was %v", count)
+	}
+}
+
+func Test_close_stops_the_janitor(t *testing.T) {
+	cache := NewCacheWithJanitor(100, 1*time.Millisecond)
+
+	if err := cache.Close(); err != nil {
+		t.Errorf("unexpected error closing cache: %v", err)
+	}
+
+	// closing twice should not panic or block
+	if err := cache.Close(); err != nil {
+		t.Errorf("unexpected error on second close: %v", err)
+	}
+}
+
+func Test_close_on_cache_without_janitor_is_a_noop(t *testing.T) {
+	cache := NewCache(100)
+
+	if err := cache.Close(); err != nil {
+		t.Errorf("unexpected error closing cache without a janitor: %v", err)
+	}
+}
+
 func Test_expired_values_are_not_counted_against_stats(t *testing.T) {
 	cache := NewCache(2)
 	cache.SetBytes("key1", ToBuffer("a"), 1*time.Millisecond)